@@ -0,0 +1,118 @@
+package bom
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanuber/go-license"
+)
+
+func TestFailOn(t *testing.T) {
+	entries := []Entry{
+		{Module: "example.com/a", Version: "v1.0.0", License: license.New("MIT", "")},
+		{Module: "example.com/b", Version: "v1.0.0", License: license.New("GPL-3.0", "")},
+	}
+
+	if err := FailOn(entries, []string{"MIT", "GPL-3.0"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err := FailOn(entries, []string{"MIT"})
+	if err == nil {
+		t.Fatalf("expected error for disallowed license")
+	}
+	if !strings.Contains(err.Error(), "example.com/b") {
+		t.Fatalf("expected error to name the violating module: %s", err)
+	}
+}
+
+func TestFailOn_unknownLicense(t *testing.T) {
+	entries := []Entry{
+		{Module: "example.com/a", Version: "v1.0.0", License: nil},
+	}
+
+	err := FailOn(entries, []string{"MIT"})
+	if err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Fatalf("expected error naming an unknown license, got: %v", err)
+	}
+}
+
+func TestDetectLicense_subtree(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-license-bom")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(root)
+
+	sub := filepath.Join(root, "subpkg")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	mit := "Permission is hereby granted, free of charge, to any person " +
+		"obtaining a copy of this software and associated documentation " +
+		"files (the \"Software\"), to deal in the Software without " +
+		"restriction."
+	if err := ioutil.WriteFile(filepath.Join(sub, "LICENSE"), []byte(mit), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l, confidence, err := detectLicense(root)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "MIT" {
+		t.Fatalf("expected MIT, got: %s", l.Type)
+	}
+	if confidence <= 0 {
+		t.Fatalf("expected a positive confidence, got: %f", confidence)
+	}
+}
+
+func TestDetectLicense_none(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-license-bom")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(root)
+
+	if _, _, err := detectLicense(root); err == nil {
+		t.Fatalf("expected error when no license exists anywhere in the tree")
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	entries := []Entry{
+		{Module: "example.com/a", Version: "v1.0.0", License: license.New("MIT", ""), Confidence: 1.0},
+	}
+
+	out, err := ToJSON(entries)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(out), "example.com/a") {
+		t.Fatalf("unexpected JSON output: %s", out)
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	entries := []Entry{
+		{Module: "example.com/a", Version: "v1.0.0", License: license.New("MIT", ""), Confidence: 1.0},
+	}
+
+	out, err := ToCSV(entries)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header + 1 row, got: %#v", lines)
+	}
+	if !strings.Contains(lines[1], "example.com/a") {
+		t.Fatalf("unexpected CSV row: %s", lines[1])
+	}
+}
@@ -0,0 +1,212 @@
+package license
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// DefaultMatchThreshold is the minimum coverage, expressed as a fraction of
+// matched shingles, that a candidate license template must reach before it
+// is reported as a Match by Classify.
+const DefaultMatchThreshold = 0.75
+
+// shingleSize is the number of words grouped together to form a single
+// comparable unit ("shingle") when scanning text for template coverage.
+const shingleSize = 10
+
+// Match describes a single license template that was found, at or above the
+// configured threshold, while classifying a block of text. Coverage is the
+// fraction of the shingles within the matched span (Start to End) that were
+// located in the template. Start and End are word offsets into the normalized text
+// delimiting the span in which the match was found.
+type Match struct {
+	Type     string
+	Coverage float64
+	Start    int
+	End      int
+}
+
+// tokenize lower-cases and splits text on whitespace, returning the
+// resulting words.
+func tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+// shingleWindow is a single contiguous n-word window over a token stream.
+// Text is used for shingle-set membership; Start is the window's word
+// offset, which Classify uses to localize a match to the span of text it
+// actually came from.
+type shingleWindow struct {
+	Text  string
+	Start int
+}
+
+// shingleWindows breaks tokens into overlapping windows of size n. If there
+// are fewer tokens than n, the whole token set is returned as a single
+// window starting at offset 0.
+func shingleWindows(tokens []string, n int) []shingleWindow {
+	if len(tokens) <= n {
+		return []shingleWindow{{Text: strings.Join(tokens, " ")}}
+	}
+
+	out := make([]shingleWindow, 0, len(tokens)-n+1)
+	for i := 0; i+n <= len(tokens); i++ {
+		out = append(out, shingleWindow{Text: strings.Join(tokens[i:i+n], " "), Start: i})
+	}
+	return out
+}
+
+// shingles breaks tokens into overlapping windows of size n, joined back
+// into strings for comparison. If there are fewer tokens than n, the whole
+// token set is returned as a single shingle.
+func shingles(tokens []string, n int) []string {
+	windows := shingleWindows(tokens, n)
+	out := make([]string, len(windows))
+	for i, w := range windows {
+		out[i] = w.Text
+	}
+	return out
+}
+
+// candidateMatch is a Match still awaiting the overlap-suppression pass in
+// Classify; Matched is the absolute number of shingles that were found,
+// which is what ranks candidates against each other once their spans
+// overlap.
+type candidateMatch struct {
+	Match
+	Matched int
+}
+
+// Classify scans the license's text against KnownLicenses, the same
+// embedded SPDX corpus GuessType uses, and returns every template whose
+// shingle coverage meets or exceeds threshold, ranked from highest to
+// lowest coverage. A threshold of 0 uses DefaultMatchThreshold. On success,
+// License.Type is set to the highest-coverage match.
+//
+// Coverage is computed per template, not against the document as a whole:
+// for each template, Classify first locates the span of text whose
+// shingles matched that template (Start/End), then reports coverage as the
+// fraction of that span's own shingles which matched. Scoping the
+// denominator to the matched span, rather than the whole input, is what
+// lets Classify recognize multi-license files and dual-licensed
+// headers — a document containing both an MIT and an Apache-2.0 block
+// scores each one against only the text it actually occupies, instead of
+// having each match diluted by the other license's unrelated shingles. The
+// same scoping lets a short or partial excerpt score highly against a much
+// longer canonical license text, since the span (and its shingle count)
+// shrinks down to the excerpt itself.
+//
+// Many permissive licenses share near-identical boilerplate (the "AS IS"
+// warranty disclaimer, for instance), so a short shared passage can by
+// itself reach full local coverage against an unrelated template. Classify
+// guards against reporting these as independent matches: candidates are
+// considered in order of matched shingles, most first, and a candidate
+// whose span overlaps an already kept match by more than half of its own
+// span is dropped, on the assumption that
+// a match nested entirely inside a much larger one is an artifact of
+// shared wording rather than a second license.
+func (l *License) Classify(threshold float64) ([]Match, error) {
+	if threshold <= 0 {
+		threshold = DefaultMatchThreshold
+	}
+
+	comp := normalize(l.Text)
+	tokens := tokenize(comp)
+	textWindows := shingleWindows(tokens, shingleSize)
+	if len(textWindows) == 0 {
+		return nil, errors.New(ErrUnrecognizedLicense)
+	}
+
+	var candidates []candidateMatch
+	for _, id := range KnownLicenses {
+		templateShingles := templateShingleSets[id]
+		if len(templateShingles) == 0 {
+			continue
+		}
+
+		matchedShingles := make(map[string]struct{})
+		start, end := -1, -1
+		for _, w := range textWindows {
+			if _, ok := templateShingles[w.Text]; !ok {
+				continue
+			}
+			matchedShingles[w.Text] = struct{}{}
+			if start == -1 {
+				start = w.Start
+			}
+			end = w.Start + shingleSize - 1
+		}
+		if len(matchedShingles) == 0 {
+			continue
+		}
+		if end >= len(tokens) {
+			end = len(tokens) - 1
+		}
+
+		spanShingles := shingleSet(strings.Join(tokens[start:end+1], " "))
+		coverage := float64(len(matchedShingles)) / float64(len(spanShingles))
+		if coverage < threshold {
+			continue
+		}
+
+		candidates = append(candidates, candidateMatch{
+			Match: Match{
+				Type:     id,
+				Coverage: coverage,
+				Start:    start,
+				End:      end,
+			},
+			Matched: len(matchedShingles),
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New(ErrUnrecognizedLicense)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Matched != candidates[j].Matched {
+			return candidates[i].Matched > candidates[j].Matched
+		}
+		return candidates[i].Type < candidates[j].Type
+	})
+
+	var matches []Match
+	for _, c := range candidates {
+		if overlapsExistingMatch(c.Match, matches) {
+			continue
+		}
+		matches = append(matches, c.Match)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Coverage > matches[j].Coverage
+	})
+
+	l.Type = matches[0].Type
+	return matches, nil
+}
+
+// overlapsExistingMatch reports whether m's span overlaps any of existing's
+// spans by more than half of m's own length, i.e. whether m is
+// substantially nested inside a match that was already accepted.
+func overlapsExistingMatch(m Match, existing []Match) bool {
+	length := m.End - m.Start + 1
+	for _, e := range existing {
+		lo, hi := m.Start, m.End
+		if e.Start > lo {
+			lo = e.Start
+		}
+		if e.End < hi {
+			hi = e.End
+		}
+		if hi < lo {
+			continue
+		}
+		if overlap := hi - lo + 1; float64(overlap)/float64(length) > 0.5 {
+			return true
+		}
+	}
+	return false
+}
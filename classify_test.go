@@ -0,0 +1,79 @@
+package license
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	l := New("", "Permission is hereby granted, free of charge, to any "+
+		"person obtaining a copy of this software and associated "+
+		"documentation files (the \"Software\"), to deal in the Software "+
+		"without restriction.")
+
+	matches, err := l.Classify(0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(matches) == 0 {
+		t.Fatalf("expected at least one match")
+	}
+	if matches[0].Type != LicenseMIT {
+		t.Fatalf("expected top match to be MIT, got: %s", matches[0].Type)
+	}
+	if matches[0].Coverage < DefaultMatchThreshold {
+		t.Fatalf("expected coverage >= %f, got: %f",
+			DefaultMatchThreshold, matches[0].Coverage)
+	}
+	if l.Type != LicenseMIT {
+		t.Fatalf("expected License.Type to be set to MIT, got: %s", l.Type)
+	}
+}
+
+func TestClassify_unrecognized(t *testing.T) {
+	l := New("", "No license text of any kind here.")
+
+	if _, err := l.Classify(0); err == nil {
+		t.Fatalf("expected error classifying unrecognizable text")
+	}
+}
+
+func TestClassify_multiLicense(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "spdx", "MIT.txt"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	apache, err := ioutil.ReadFile(filepath.Join("fixtures", "spdx", "Apache-2.0.txt"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l := New("", string(mit)+"\n"+string(apache))
+
+	matches, err := l.Classify(0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected exactly 2 matches, got: %#v", matches)
+	}
+
+	found := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		found[m.Type] = true
+	}
+	if !found[LicenseMIT] || !found[LicenseApache20] {
+		t.Fatalf("expected MIT and %s, got: %#v", LicenseApache20, matches)
+	}
+}
+
+func TestClassify_threshold(t *testing.T) {
+	l := New("", "Permission is hereby granted, free of charge, to any "+
+		"person obtaining a copy of this software")
+
+	// An unreasonably high threshold should exclude every template.
+	if _, err := l.Classify(1.1); err == nil {
+		t.Fatalf("expected error with an unreachable threshold")
+	}
+}
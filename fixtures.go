@@ -0,0 +1,155 @@
+package license
+
+import (
+	"embed"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed fixtures/spdx/*.txt
+var spdxFixtures embed.FS
+
+// GuessThreshold is the minimum similarity score, out of 1.0, that a
+// candidate template must reach before GuessType will accept it as a
+// match. It may be lowered to recognize more heavily abbreviated license
+// text, at the cost of more false positives.
+var GuessThreshold = 0.5
+
+// KnownLicenses lists every SPDX identifier go-license can recognize, in
+// alphabetical order. It is populated at init time from the embedded
+// fixtures/spdx corpus, so recognizing an additional license only requires
+// dropping its canonical text in as a new fixture file.
+var KnownLicenses []string
+
+// templateShingleSets holds, per SPDX identifier, the set of shingles
+// derived from that license's canonical, normalized text.
+var templateShingleSets = map[string]map[string]struct{}{}
+
+// templateText holds, per SPDX identifier, the canonical, normalized text
+// itself (as opposed to its shingle set). It backs the short-text fallback
+// in GuessType, which needs the template's word order rather than just its
+// shingle membership.
+var templateText = map[string]string{}
+
+// placeholderRegexp matches the copyright-holder and year placeholders
+// commonly left in canonical license templates, such as "<year>",
+// "[fullname]", or "<https://fsf.org/>".
+var placeholderRegexp = regexp.MustCompile(`[<\[][^<>\[\]]*[>\]]`)
+
+func init() {
+	entries, err := spdxFixtures.ReadDir("fixtures/spdx")
+	if err != nil {
+		// The fixture corpus is embedded at build time; a failure here
+		// means the build itself is broken, not a runtime condition.
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".txt")
+
+		data, err := spdxFixtures.ReadFile("fixtures/spdx/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		text := normalizeTemplate(string(data))
+
+		KnownLicenses = append(KnownLicenses, id)
+		templateText[id] = text
+		templateShingleSets[id] = shingleSet(text)
+		licenseTable[id] = struct{}{}
+	}
+
+	sort.Strings(KnownLicenses)
+}
+
+// normalizeTemplate normalizes canonical license text the same way as
+// normalize, and additionally strips copyright-holder and year
+// placeholders, which never appear in a real, filled-in license file.
+func normalizeTemplate(text string) string {
+	return normalize(placeholderRegexp.ReplaceAllLiteralString(text, ""))
+}
+
+// shingleSet tokenizes text and returns its shingles as a set.
+func shingleSet(text string) map[string]struct{} {
+	tokens := tokenize(text)
+	set := make(map[string]struct{}, len(tokens))
+	for _, s := range shingles(tokens, shingleSize) {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+// GuessType will scan license text and attempt to guess what license type
+// it describes. It will return the license type on success, or an error if
+// it cannot accurately guess the license type.
+//
+// Every known license, listed in KnownLicenses, carries a set of shingles
+// derived from its canonical text. The input text is scored against each
+// template by the fraction of the input's own shingles that also appear in
+// the template; the highest-scoring template at or above GuessThreshold
+// wins. This allows full license texts to be recognized without requiring
+// a hand-picked substring per license.
+//
+// Text shorter than a single shingle window is handled separately, by
+// guessShortType: such a candidate collapses to one joined shingle that
+// can only ever equal one of a template's shingles in the degenerate case
+// where the template itself is that short, so shingle coverage can never
+// recognize it. This is the common case for abbreviated or partial license
+// blocks, such as a bare differentiating URL or clause lifted out of a
+// much longer license.
+func (l *License) GuessType() error {
+	comp := normalize(l.Text)
+	if len(tokenize(comp)) < shingleSize {
+		return l.guessShortType(comp)
+	}
+
+	inputShingles := shingleSet(comp)
+	if len(inputShingles) == 0 {
+		return errors.New(ErrUnrecognizedLicense)
+	}
+
+	var bestType string
+	var bestScore float64
+
+	for _, id := range KnownLicenses {
+		template := templateShingleSets[id]
+
+		var found int
+		for s := range inputShingles {
+			if _, ok := template[s]; ok {
+				found++
+			}
+		}
+
+		score := float64(found) / float64(len(inputShingles))
+		if score > bestScore {
+			bestScore = score
+			bestType = id
+		}
+	}
+
+	if bestScore < GuessThreshold {
+		return errors.New(ErrUnrecognizedLicense)
+	}
+
+	l.Type = bestType
+	return nil
+}
+
+// guessShortType recognizes a candidate too short to form a full shingle
+// window by looking for it as a literal run of characters within each
+// template's canonical text. This is enough to recognize a differentiating
+// URL or clause quoted out of a much longer license, even though it can't
+// contribute a matching shingle.
+func (l *License) guessShortType(comp string) error {
+	for _, id := range KnownLicenses {
+		if strings.Contains(templateText[id], comp) {
+			l.Type = id
+			return nil
+		}
+	}
+	return errors.New(ErrUnrecognizedLicense)
+}
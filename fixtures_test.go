@@ -0,0 +1,52 @@
+package license
+
+import "testing"
+
+func TestKnownLicenses(t *testing.T) {
+	if len(KnownLicenses) == 0 {
+		t.Fatalf("KnownLicenses not initialized: %#v", KnownLicenses)
+	}
+	if len(templateShingleSets) != len(KnownLicenses) {
+		t.Fatalf("templateShingleSets not initialized: %#v", templateShingleSets)
+	}
+	for _, id := range KnownLicenses {
+		if _, ok := licenseTable[id]; !ok {
+			t.Fatalf("licenseTable missing entry for %s", id)
+		}
+	}
+}
+
+func TestGuessType(t *testing.T) {
+	l := New("", "Permission is hereby granted, free of charge, to any "+
+		"person obtaining a copy of this software and associated "+
+		"documentation files (the \"Software\"), to deal in the Software "+
+		"without restriction, including without limitation the rights to "+
+		"use, copy, modify, merge, publish, distribute, sublicense, and/or "+
+		"sell copies of the Software.")
+
+	if err := l.GuessType(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != LicenseMIT {
+		t.Fatalf("expected MIT, got: %s", l.Type)
+	}
+}
+
+func TestGuessType_abbreviated(t *testing.T) {
+	l := New("", "http://www.apache.org/licenses/")
+
+	if err := l.GuessType(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != LicenseApache20 {
+		t.Fatalf("expected %s, got: %s", LicenseApache20, l.Type)
+	}
+}
+
+func TestGuessType_unrecognized(t *testing.T) {
+	l := New("", "This text bears no resemblance to any known license.")
+
+	if err := l.GuessType(); err == nil {
+		t.Fatalf("expected error guessing unrecognizable text")
+	}
+}
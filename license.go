@@ -1,9 +1,11 @@
 package license
 
 import (
-	"bytes"
+	"crypto/sha256"
 	"errors"
+	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -16,22 +18,35 @@ const (
 	LicenseNewBSD    = "NewBSD"
 	LicenseFreeBSD   = "FreeBSD"
 	LicenseApache20  = "Apache-2.0"
+	LicenseMPL11     = "MPL-1.1"
 	LicenseMPL20     = "MPL-2.0"
 	LicenseGPL20     = "GPL-2.0"
 	LicenseGPL30     = "GPL-3.0"
+	LicenseLGPL20    = "LGPL-2.0"
 	LicenseLGPL21    = "LGPL-2.1"
 	LicenseLGPL30    = "LGPL-3.0"
 	LicenseAGPL30    = "AGPL-3.0"
 	LicenseCDDL10    = "CDDL-1.0"
 	LicenseEPL10     = "EPL-1.0"
+	LicenseEPL20     = "EPL-2.0"
+	LicenseBSL10     = "BSL-1.0"
+	LicenseWTFPL     = "WTFPL"
+	LicenseZlib      = "Zlib"
+	LicenseOFL11     = "OFL-1.1"
 	LicenseUnlicense = "Unlicense"
 
 	// Various error messages.
 	ErrNoLicenseFile       = "license: unable to find any license file"
 	ErrUnrecognizedLicense = "license: could not guess license type"
 	ErrMultipleLicenses    = "license: multiple license files found"
+	ErrLicenseTooLarge     = "license: license text exceeds MaxLicenseSize"
 )
 
+// MaxLicenseSize is the largest license file go-license will read. Files
+// larger than this are refused with ErrLicenseTooLarge rather than read
+// into memory, as a guard against pathological input.
+var MaxLicenseSize int64 = 1 << 20 // 1 MiB
+
 var (
 	// Base names of guessable license files.
 	fileNames = []string{
@@ -52,10 +67,19 @@ var (
 		".txt",
 	}
 
+	// DefaultLicenseFiles is the full set of file names, generated from
+	// fileNames and fileExtensions, that LicenseFilesInDir recognizes as
+	// license files. It is exposed so callers can inspect or document the
+	// exact set of names go-license looks for.
+	DefaultLicenseFiles []string
+
 	// Lookup tables used for license file names and license types. We
-	// use a poor man's set here to get O(1) lookups.
+	// use a poor man's set here to get O(1) lookups. licenseTable is
+	// allocated here, rather than in init, so that it is guaranteed to
+	// exist before fixtures.go's init populates it from the embedded
+	// license corpus.
 	fileTable    map[string]struct{}
-	licenseTable map[string]struct{}
+	licenseTable = make(map[string]struct{})
 
 	// Regular expressions used for normalizing license text.
 	newlineRegexp = regexp.MustCompile("(\r\n|\n)")
@@ -66,32 +90,15 @@ var (
 func init() {
 	// Generate the list of known file names.
 	size := len(fileNames) * len(fileExtensions)
+	DefaultLicenseFiles = make([]string, 0, size)
 	fileTable = make(map[string]struct{}, size)
 	for _, file := range fileNames {
 		for _, ext := range fileExtensions {
-			fileTable[file+ext] = struct{}{}
+			name := file + ext
+			DefaultLicenseFiles = append(DefaultLicenseFiles, name)
+			fileTable[name] = struct{}{}
 		}
 	}
-
-	// Initialize the license types.
-	licenseTable = make(map[string]struct{})
-	for _, l := range []string{
-		LicenseMIT,
-		LicenseNewBSD,
-		LicenseFreeBSD,
-		LicenseApache20,
-		LicenseMPL20,
-		LicenseGPL20,
-		LicenseGPL30,
-		LicenseLGPL21,
-		LicenseLGPL30,
-		LicenseAGPL30,
-		LicenseCDDL10,
-		LicenseEPL10,
-		LicenseUnlicense,
-	} {
-		licenseTable[l] = struct{}{}
-	}
 }
 
 // LicenseFilesInDir will scan the given directory for files which match our
@@ -118,6 +125,7 @@ type License struct {
 	Type string // The type of license in use
 	Text string // License text data
 	File string // The path to the source file, if any
+	Line int    // The line within File the license was found on, if any
 }
 
 // New creates a new License from explicitly passed license type and data
@@ -131,11 +139,24 @@ func New(licenseType, licenseText string) *License {
 
 // NewFromFile will attempt to load a license from a file on disk, and guess the
 // type of license based on the bytes read.
+//
+// The file is streamed through a reader capped at MaxLicenseSize+1 bytes,
+// so that a file larger than the limit is rejected with ErrLicenseTooLarge
+// instead of being read into memory in full.
 func NewFromFile(path string) (*License, error) {
-	licenseText, err := ioutil.ReadFile(path)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	licenseText, err := ioutil.ReadAll(io.LimitReader(f, MaxLicenseSize+1))
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(licenseText)) > MaxLicenseSize {
+		return nil, errors.New(ErrLicenseTooLarge)
+	}
 
 	l := &License{
 		Text: string(licenseText),
@@ -150,14 +171,99 @@ func NewFromFile(path string) (*License, error) {
 }
 
 // NewFromDir will search a directory for well-known and accepted license file
-// names, and if one is found, read in its content and guess the license type.
+// names, and if exactly one is found, read in its content and guess the
+// license type.
+//
+// It is implemented in terms of NewAllFromDir, so multiple license files
+// that are byte-for-byte duplicates of one another (once normalized) are
+// treated as a single license rather than an error; ErrMultipleLicenses is
+// only returned when genuinely distinct license files are found.
 func NewFromDir(dir string) (*License, error) {
-	file, err := GuessFile(dir)
+	licenses, err := NewAllFromDir(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewFromFile(file)
+	if len(licenses) > 1 {
+		return nil, errors.New(ErrMultipleLicenses)
+	}
+
+	return licenses[0], nil
+}
+
+// NewAllFromDir searches a directory for well-known and accepted license
+// file names and returns a License for each one found, deduplicated by the
+// SHA-256 sum of their normalized text. This means a directory shipping
+// dual licenses under identical terms but different file names (e.g.
+// LICENSE and LICENSE.md with the same content) yields one entry, while a
+// directory with genuinely different license files (e.g. LICENSE-MIT and
+// LICENSE-APACHE) yields one entry per distinct license.
+func NewAllFromDir(dir string) ([]*License, error) {
+	files, err := LicenseFilesInDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New(ErrNoLicenseFile)
+	}
+
+	seen := make(map[[sha256.Size]byte]struct{}, len(files))
+	var out []*License
+	for _, name := range files {
+		l, err := NewFromFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256([]byte(normalize(l.Text)))
+		if _, ok := seen[sum]; ok {
+			continue
+		}
+		seen[sum] = struct{}{}
+
+		out = append(out, l)
+	}
+
+	return out, nil
+}
+
+// errFoundInSubtree is an internal sentinel used by NewFromSubtree to stop
+// filepath.Walk as soon as a license file is found.
+var errFoundInSubtree = errors.New("license: found license in subtree")
+
+// NewFromSubtree recursively searches dir's subdirectories, in the lexical,
+// depth-first order filepath.Walk visits them, for the first one containing a license
+// file, and returns a License for it. Unlike NewFromDir, dir itself is not
+// considered; this is meant as a fallback for the common case of a
+// sub-package shipping its own license file when the root of the tree has
+// none.
+func NewFromSubtree(dir string) (*License, error) {
+	var found *License
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || path == dir {
+			return nil
+		}
+
+		l, lerr := NewFromDir(path)
+		if lerr != nil {
+			return nil
+		}
+
+		found = l
+		return errFoundInSubtree
+	})
+	if err != nil && err != errFoundInSubtree {
+		return nil, err
+	}
+	if found == nil {
+		return nil, errors.New(ErrNoLicenseFile)
+	}
+
+	return found, nil
 }
 
 // Recognized determines if the license is known to go-license.
@@ -184,20 +290,11 @@ func GuessFile(dir string) (string, error) {
 	}
 }
 
-// GuessType will scan license text and attempt to guess what license type it
-// describes. It will return the license type on success, or an error if it
-// cannot accurately guess the license type.
-//
-// This method is a hack. It might be more accurate to also scan the entire body
-// of license text and compare it using an algorithm like Jaro-Winkler or
-// Levenshtein against a generic version. The problem is that some of the
-// common licenses, such as GPL-family licenses, are quite large, and running
-// these algorithms against them is considerably more expensive and is still not
-// completely deterministic on which license is in play. For now, we will just
-// scan until we find differentiating strings and call that good-enuf.gov.
-func (l *License) GuessType() error {
-	// Lower case everything to make comparison more adaptable.
-	comp := strings.ToLower(l.Text)
+// normalize lower-cases text and collapses newlines and runs of whitespace
+// into single spaces, producing a canonical form suitable for comparison
+// against known license text regardless of the source's own formatting.
+func normalize(text string) string {
+	comp := strings.ToLower(text)
 
 	// Kill the newlines, since it is not clear if the provided license will
 	// contain them or not, and either way it does not change the terms of the
@@ -210,68 +307,5 @@ func (l *License) GuessType() error {
 	// the exact space matching.
 	comp = spaceRegexp.ReplaceAllLiteralString(comp, " ")
 
-	switch {
-	case scan(comp, "permission is hereby granted, free of charge, to any "+
-		"person obtaining a copy of this software"):
-		l.Type = LicenseMIT
-
-	case scan(comp, "permission to use, copy, modify, and/or distribute this "+
-		"software for any"):
-		l.Type = LicenseISC
-
-	case scan(comp, "apache license version 2.0, january 2004") ||
-		scan(comp, "http://www.apache.org/licenses/license-2.0"):
-		l.Type = LicenseApache20
-
-	case scan(comp, "gnu general public license version 2, june 1991"):
-		l.Type = LicenseGPL20
-
-	case scan(comp, "gnu general public license version 3, 29 june 2007"):
-		l.Type = LicenseGPL30
-
-	case scan(comp, "gnu lesser general public license version 2.1, "+
-		"february 1999"):
-		l.Type = LicenseLGPL21
-
-	case scan(comp, "gnu lesser general public license version 3, "+
-		"29 june 2007"):
-		l.Type = LicenseLGPL30
-
-	case scan(comp, "gnu affero general public license "+
-		"version 3, 19 november 2007"):
-		l.Type = LicenseAGPL30
-
-	case scan(comp, "mozilla public license") && scan(comp, "version 2.0"):
-		l.Type = LicenseMPL20
-
-	case scan(comp, "redistribution and use in source and binary forms"):
-		switch {
-		case scan(comp, "neither the name of"):
-			l.Type = LicenseNewBSD
-		default:
-			l.Type = LicenseFreeBSD
-		}
-
-	case scan(comp, "common development and distribution license (cddl) "+
-		"version 1.0"):
-		l.Type = LicenseCDDL10
-
-	case scan(comp, "eclipse public license - v 1.0"):
-		l.Type = LicenseEPL10
-
-	case scan(comp, "this is free and unencumbered software released into "+
-		"the public domain"):
-		l.Type = LicenseUnlicense
-
-	default:
-		return errors.New(ErrUnrecognizedLicense)
-	}
-
-	return nil
-}
-
-// scan is used to find substrings. It type-casts to byte slices because
-// bytes is an order of magnitude faster than its strings counterpart.
-func scan(text, pattern string) bool {
-	return bytes.Contains([]byte(text), []byte(pattern))
+	return comp
 }
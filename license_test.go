@@ -36,7 +36,7 @@ func TestNewLicense(t *testing.T) {
 }
 
 func TestNewFromFile(t *testing.T) {
-	lf := filepath.Join("fixtures", "licenses", "MIT")
+	lf := filepath.Join("fixtures", "spdx", "MIT.txt")
 
 	lh, err := os.Open(lf)
 	if err != nil {
@@ -98,7 +98,7 @@ func TestNewFromDir(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
-	lh, err := os.Open(filepath.Join("fixtures", "licenses", "MIT"))
+	lh, err := os.Open(filepath.Join("fixtures", "spdx", "MIT.txt"))
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -153,12 +153,15 @@ func TestNewFromDir_fails(t *testing.T) {
 		t.Fatalf("expected error loading non-existent directory")
 	}
 
-	// Fails if multiple licenses are found. Also checks that casing is
-	// ignored in the license file name.
-	if _, err := os.Create(filepath.Join(d, "LICENSE.txt")); err != nil {
+	// Fails if multiple, genuinely distinct licenses are found. Also
+	// checks that casing is ignored in the license file name.
+	mit := "Permission is hereby granted, free of charge, to any person " +
+		"obtaining a copy of this software"
+	apache := "Apache License Version 2.0, January 2004"
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE.txt"), []byte(mit), 0644); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if _, err := os.Create(filepath.Join(d, "copying.RST")); err != nil {
+	if err := ioutil.WriteFile(filepath.Join(d, "copying.RST"), []byte(apache), 0644); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	_, err = NewFromDir(d)
@@ -188,7 +191,7 @@ func TestLicenseRecognized(t *testing.T) {
 
 func TestLicenseTypes(t *testing.T) {
 	for _, ltype := range KnownLicenses {
-		file := filepath.Join("fixtures", "licenses", ltype)
+		file := filepath.Join("fixtures", "spdx", ltype+".txt")
 		fh, err := os.Open(file)
 		if err != nil {
 			t.Fatalf("err: %s", err)
@@ -219,3 +222,110 @@ func TestLicenseTypes_Abbreviated(t *testing.T) {
 		t.Fatalf("\nexpected: %s\ngot: %s", LicenseApache20, l.Type)
 	}
 }
+
+func TestNewFromFile_tooLarge(t *testing.T) {
+	f, err := ioutil.TempFile("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	orig := MaxLicenseSize
+	MaxLicenseSize = 4
+	defer func() { MaxLicenseSize = orig }()
+
+	if _, err := f.WriteString("way too long"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = NewFromFile(f.Name())
+	if err == nil || err.Error() != ErrLicenseTooLarge {
+		t.Fatalf("expect %q, got: %v", ErrLicenseTooLarge, err)
+	}
+}
+
+func TestNewFromSubtree(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	sub := filepath.Join(d, "subpkg")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	mit := "Permission is hereby granted, free of charge, to any person " +
+		"obtaining a copy of this software"
+	if err := ioutil.WriteFile(filepath.Join(sub, "LICENSE"), []byte(mit), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l, err := NewFromSubtree(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "MIT" {
+		t.Fatalf("unexpected license type: %s", l.Type)
+	}
+	if l.File != filepath.Join(sub, "LICENSE") {
+		t.Fatalf("unexpected file path: %s", l.File)
+	}
+
+	// A top-level license file is not considered; only subdirectories are.
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE"), []byte(mit), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l, err = NewFromSubtree(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.File != filepath.Join(sub, "LICENSE") {
+		t.Fatalf("unexpected file path: %s", l.File)
+	}
+
+	// Fails if no subdirectory contains a license file.
+	empty, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(empty)
+
+	if _, err := NewFromSubtree(empty); err == nil {
+		t.Fatalf("expected error when no subdirectory contains a license file")
+	}
+}
+
+func TestNewAllFromDir_dedup(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	mit := "Permission is hereby granted, free of charge, to any person " +
+		"obtaining a copy of this software"
+
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE"), []byte(mit), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE.md"), []byte(mit), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	licenses, err := NewAllFromDir(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(licenses) != 1 {
+		t.Fatalf("expected identical license files to dedup to 1, got %d", len(licenses))
+	}
+
+	// NewFromDir now succeeds for this case too, since it dedups down to
+	// a single license.
+	if _, err := NewFromDir(d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
@@ -0,0 +1,94 @@
+package license
+
+import "fmt"
+
+// Category describes how permissive a license is with respect to
+// redistribution of the software it covers.
+type Category string
+
+const (
+	// CategoryNotice licenses require only that copyright and license
+	// notices be preserved (MIT, BSD, ISC, Apache, MPL).
+	CategoryNotice Category = "notice"
+
+	// CategoryReciprocal licenses require that modifications, and in some
+	// cases the whole combined work, be distributed under the same terms
+	// (the GPL family).
+	CategoryReciprocal Category = "reciprocal"
+
+	// CategoryRestricted licenses impose conditions beyond notice
+	// preservation that stop short of full reciprocity, such as
+	// per-file source disclosure.
+	CategoryRestricted Category = "restricted"
+
+	// CategoryForbidden covers anything go-license could not recognize,
+	// or that a caller has explicitly classified as unsuitable for
+	// redistribution.
+	CategoryForbidden Category = "forbidden"
+)
+
+// ErrPolicyViolation begins the message of the error NewFromDirWithPolicy
+// returns when the detected license is not present in the caller's allow-list.
+const ErrPolicyViolation = "license: detected license is not in the allowed list"
+
+// RedistributableTypes maps every known license type to the category
+// describing how it may be redistributed.
+var RedistributableTypes = map[string]Category{
+	LicenseMIT:       CategoryNotice,
+	LicenseISC:       CategoryNotice,
+	LicenseNewBSD:    CategoryNotice,
+	LicenseFreeBSD:   CategoryNotice,
+	LicenseApache20:  CategoryNotice,
+	LicenseMPL11:     CategoryNotice,
+	LicenseMPL20:     CategoryNotice,
+	LicenseBSL10:     CategoryNotice,
+	LicenseWTFPL:     CategoryNotice,
+	LicenseZlib:      CategoryNotice,
+	LicenseGPL20:     CategoryReciprocal,
+	LicenseGPL30:     CategoryReciprocal,
+	LicenseLGPL20:    CategoryReciprocal,
+	LicenseLGPL21:    CategoryReciprocal,
+	LicenseLGPL30:    CategoryReciprocal,
+	LicenseAGPL30:    CategoryReciprocal,
+	LicenseOFL11:     CategoryReciprocal,
+	LicenseCDDL10:    CategoryRestricted,
+	LicenseEPL10:     CategoryRestricted,
+	LicenseEPL20:     CategoryRestricted,
+	LicenseUnlicense: CategoryNotice,
+}
+
+// Category returns the redistribution category of the license. An
+// unrecognized or unmapped license type is always CategoryForbidden.
+func (l *License) Category() Category {
+	c, ok := RedistributableTypes[l.Type]
+	if !ok {
+		return CategoryForbidden
+	}
+	return c
+}
+
+// Redistributable reports whether the license permits redistribution at
+// all, i.e. whether its category is anything other than
+// CategoryForbidden.
+func (l *License) Redistributable() bool {
+	return l.Category() != CategoryForbidden
+}
+
+// NewFromDirWithPolicy behaves like NewFromDir, but additionally checks the
+// detected license type against allowed, a list of acceptable license
+// types. If the detected license is not in the list, it returns an error
+// whose message is ErrPolicyViolation followed by the detected type.
+func NewFromDirWithPolicy(dir string, allowed []string) (*License, error) {
+	l, err := NewFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range allowed {
+		if l.Type == a {
+			return l, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %s", ErrPolicyViolation, l.Type)
+}
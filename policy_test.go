@@ -0,0 +1,63 @@
+package license
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLicenseCategory(t *testing.T) {
+	l := New(LicenseMIT, "")
+	if l.Category() != CategoryNotice {
+		t.Fatalf("expected %s, got: %s", CategoryNotice, l.Category())
+	}
+
+	l = New(LicenseGPL30, "")
+	if l.Category() != CategoryReciprocal {
+		t.Fatalf("expected %s, got: %s", CategoryReciprocal, l.Category())
+	}
+
+	l = New("SomeUnknownLicense", "")
+	if l.Category() != CategoryForbidden {
+		t.Fatalf("expected %s, got: %s", CategoryForbidden, l.Category())
+	}
+}
+
+func TestLicenseRedistributable(t *testing.T) {
+	l := New(LicenseApache20, "")
+	if !l.Redistributable() {
+		t.Fatalf("expected %s to be redistributable", LicenseApache20)
+	}
+
+	l = New("SomeUnknownLicense", "")
+	if l.Redistributable() {
+		t.Fatalf("expected unknown license to not be redistributable")
+	}
+}
+
+func TestNewFromDirWithPolicy(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	f, err := os.Create(filepath.Join(d, "LICENSE"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := f.WriteString("Permission is hereby granted, free of " +
+		"charge, to any person obtaining a copy of this software"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	if _, err := NewFromDirWithPolicy(d, []string{LicenseMIT}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := NewFromDirWithPolicy(d, []string{LicenseGPL30}); err == nil {
+		t.Fatalf("expected error for disallowed license")
+	}
+}
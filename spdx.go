@@ -0,0 +1,237 @@
+package license
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNoSPDXTag is returned when a file is scanned for an SPDX license
+// identifier but none is found.
+const ErrNoSPDXTag = "license: no SPDX-License-Identifier tag found"
+
+// spdxHeaderLines is the number of lines read from the top of each file
+// while looking for an SPDX-License-Identifier tag. License tags are a
+// header convention, so it is not necessary to scan entire files.
+const spdxHeaderLines = 100
+
+// spdxTagRegexp matches an SPDX-License-Identifier comment tag and captures
+// the SPDX expression that follows it.
+var spdxTagRegexp = regexp.MustCompile(`SPDX-License-Identifier:\s*(.+)`)
+
+// SPDXNode is a node in a parsed SPDX license expression. A leaf node
+// carries an SPDX identifier in ID and has no Operator. An internal node
+// carries an Operator ("AND", "OR", or "WITH") and one or two operands.
+type SPDXNode struct {
+	ID       string
+	Operator string
+	Left     *SPDXNode
+	Right    *SPDXNode
+}
+
+// String renders the node back into SPDX expression syntax.
+func (n *SPDXNode) String() string {
+	if n == nil {
+		return ""
+	}
+	if n.Operator == "" {
+		return n.ID
+	}
+	if n.Right == nil {
+		return fmt.Sprintf("%s %s", n.Left, n.Operator)
+	}
+	return fmt.Sprintf("(%s %s %s)", n.Left, n.Operator, n.Right)
+}
+
+// SPDXExpression parses the license's Type as an SPDX license expression
+// and returns its AST. It is intended for use with licenses produced by
+// NewFromTree, whose Type holds the raw expression found in an
+// SPDX-License-Identifier tag.
+func (l *License) SPDXExpression() (*SPDXNode, error) {
+	return ParseSPDXExpression(l.Type)
+}
+
+// ParseSPDXExpression parses an SPDX license expression, such as
+// "MIT OR Apache-2.0" or "(GPL-2.0 WITH Classpath-exception-2.0)", into an
+// SPDXNode tree.
+func ParseSPDXExpression(expr string) (*SPDXNode, error) {
+	p := &spdxParser{tokens: spdxTokenize(expr)}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("license: empty SPDX expression")
+	}
+
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("license: unexpected token %q in SPDX expression",
+			p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+// spdxTokenize splits an SPDX expression into parens, operators, and
+// identifiers.
+func spdxTokenize(expr string) []string {
+	expr = strings.ReplaceAll(expr, "(", " ( ")
+	expr = strings.ReplaceAll(expr, ")", " ) ")
+	return strings.Fields(expr)
+}
+
+// spdxParser implements a small recursive-descent parser over SPDX
+// expression tokens, honoring standard precedence: WITH binds tightest,
+// then AND, then OR.
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *spdxParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *spdxParser) parseExpr() (*SPDXNode, error) {
+	return p.parseOr()
+}
+
+func (p *spdxParser) parseOr() (*SPDXNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &SPDXNode{Operator: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *spdxParser) parseAnd() (*SPDXNode, error) {
+	left, err := p.parseWith()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.next()
+		right, err := p.parseWith()
+		if err != nil {
+			return nil, err
+		}
+		left = &SPDXNode{Operator: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *spdxParser) parseWith() (*SPDXNode, error) {
+	left, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "WITH") {
+		p.next()
+		exception, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		left = &SPDXNode{Operator: "WITH", Left: left, Right: exception}
+	}
+	return left, nil
+}
+
+func (p *spdxParser) parseAtom() (*SPDXNode, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, errors.New("license: unexpected end of SPDX expression")
+	case tok == "(":
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("license: unbalanced parens in SPDX expression")
+		}
+		return node, nil
+	default:
+		return &SPDXNode{ID: tok}, nil
+	}
+}
+
+// NewFromTree walks dir recursively and returns a License for every source
+// file that carries an SPDX-License-Identifier tag within its first
+// spdxHeaderLines lines. Each returned License has its Type set to the raw
+// SPDX expression found, its File set to the file's path, and its Line set
+// to the line the tag was found on.
+func NewFromTree(dir string) ([]*License, error) {
+	var out []*License
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		l, err := spdxFromFile(path)
+		if err != nil {
+			if err.Error() == ErrNoSPDXTag {
+				return nil
+			}
+			return err
+		}
+
+		out = append(out, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// spdxFromFile scans a single file's header for an SPDX-License-Identifier
+// tag and returns a License describing it.
+func spdxFromFile(path string) (*License, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; lineNum <= spdxHeaderLines && scanner.Scan(); lineNum++ {
+		matches := spdxTagRegexp.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+
+		expr := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(matches[1]), "*/"))
+		return &License{
+			Type: expr,
+			File: path,
+			Line: lineNum,
+		}, nil
+	}
+
+	return nil, errors.New(ErrNoSPDXTag)
+}
@@ -0,0 +1,94 @@
+package license
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSPDXExpression(t *testing.T) {
+	node, err := ParseSPDXExpression("MIT")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if node.ID != "MIT" {
+		t.Fatalf("unexpected leaf: %#v", node)
+	}
+
+	node, err = ParseSPDXExpression("MIT OR Apache-2.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if node.Operator != "OR" || node.Left.ID != "MIT" || node.Right.ID != "Apache-2.0" {
+		t.Fatalf("unexpected tree: %#v", node)
+	}
+
+	node, err = ParseSPDXExpression("(GPL-2.0 WITH Classpath-exception-2.0) AND MIT")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if node.Operator != "AND" {
+		t.Fatalf("expected top-level AND, got: %#v", node)
+	}
+	if node.Left.Operator != "WITH" || node.Left.Left.ID != "GPL-2.0" ||
+		node.Left.Right.ID != "Classpath-exception-2.0" {
+		t.Fatalf("unexpected left operand: %#v", node.Left)
+	}
+
+	if _, err := ParseSPDXExpression(""); err == nil {
+		t.Fatalf("expected error parsing empty expression")
+	}
+
+	if _, err := ParseSPDXExpression("(MIT"); err == nil {
+		t.Fatalf("expected error parsing unbalanced expression")
+	}
+}
+
+func TestLicenseSPDXExpression(t *testing.T) {
+	l := New("MIT OR Apache-2.0", "")
+
+	node, err := l.SPDXExpression()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if node.Operator != "OR" {
+		t.Fatalf("unexpected tree: %#v", node)
+	}
+}
+
+func TestNewFromTree(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	tagged := filepath.Join(d, "tagged.go")
+	contents := "// SPDX-License-Identifier: MIT\npackage main\n"
+	if err := ioutil.WriteFile(tagged, []byte(contents), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	untagged := filepath.Join(d, "untagged.go")
+	if err := ioutil.WriteFile(untagged, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	licenses, err := NewFromTree(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(licenses) != 1 {
+		t.Fatalf("expected 1 license, got %d: %#v", len(licenses), licenses)
+	}
+	if licenses[0].Type != "MIT" {
+		t.Fatalf("unexpected type: %s", licenses[0].Type)
+	}
+	if licenses[0].File != tagged {
+		t.Fatalf("unexpected file: %s", licenses[0].File)
+	}
+	if licenses[0].Line != 1 {
+		t.Fatalf("unexpected line: %d", licenses[0].Line)
+	}
+}